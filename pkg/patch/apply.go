@@ -14,6 +14,13 @@ import (
 //
 // Returns true if any value has been changed.
 func Apply(target interface{}, patch map[string]interface{}) (changed bool, err error) {
+	return ApplyWithOptions(target, patch, Options{})
+}
+
+// ApplyWithOptions behaves like Apply but lets callers opt into alternate
+// semantics via opts, such as RFC 7396 JSON Merge Patch's null-as-delete
+// handling (see ApplyMergePatch).
+func ApplyWithOptions(target interface{}, patch map[string]interface{}, opts Options) (changed bool, err error) {
 	var dst = structs.New(target)
 
 	for key, value := range patch {
@@ -24,9 +31,55 @@ func Apply(target interface{}, patch map[string]interface{}) (changed bool, err
 		}
 		dstKind := dstField.Kind()
 		dstValue := dstField.Value()
+
+		nestedOpts := opts
+		if opts.Mask != nil {
+			subFilter, allowed := opts.Mask.Filter(name)
+			if !allowed {
+				if opts.StrictMask {
+					err = fmt.Errorf("%s: field is not allowed by mask", name)
+					return
+				}
+				continue
+			}
+			nestedOpts.Mask = subFilter
+		}
+
+		if value == nil {
+			if !opts.MergePatch {
+				continue // skip, matching Apply's existing behavior
+			}
+			if !dstField.IsZero() {
+				changed = true
+			}
+			if err = dstField.Set(reflect.Zero(reflect.TypeOf(dstValue)).Interface()); err != nil {
+				return
+			}
+			continue
+		}
+
 		srcValue := reflect.ValueOf(value)
 		srcValueAsStruct, isSrcValueAStruct := value.(map[string]interface{})
 
+		// custom scalar types (time.Time, net.IP, big.Int, typed IDs, ...)
+		// decode themselves rather than going through reflect conversion
+		if !isSrcValueAStruct {
+			unmarshaled, handled, uErr := unmarshalScalar(reflect.TypeOf(dstValue), value)
+			if uErr != nil {
+				err = uErr
+				return
+			}
+			if handled {
+				if !reflect.DeepEqual(unmarshaled.Interface(), dstValue) {
+					changed = true
+				}
+				if err = dstField.Set(unmarshaled.Interface()); err != nil {
+					return
+				}
+				continue
+			}
+		}
+
 		// recursive for a nil value of a pointer to struct
 		if dstKind == reflect.Pointer && dstField.IsZero() && isSrcValueAStruct {
 			targetType := reflect.TypeOf(target)
@@ -40,7 +93,7 @@ func Apply(target interface{}, patch map[string]interface{}) (changed bool, err
 			}
 			newDestStruct := reflect.New(sFieldType)
 			valueToSet := newDestStruct.Interface()
-			iChanged, iErr := Apply(valueToSet, srcValueAsStruct)
+			iChanged, iErr := ApplyWithOptions(valueToSet, srcValueAsStruct, nestedOpts)
 			if iErr != nil {
 				err = iErr
 				return
@@ -61,7 +114,7 @@ func Apply(target interface{}, patch map[string]interface{}) (changed bool, err
 		// recursive for structs and pointers to existing structs
 		if isSrcValueAStruct && (dstKind == reflect.Struct ||
 			(dstKind == reflect.Pointer && reflect.Indirect(reflect.ValueOf(dstValue)).Kind() == reflect.Struct)) {
-			iChanged, iErr := Apply(dstValue, srcValueAsStruct)
+			iChanged, iErr := ApplyWithOptions(dstValue, srcValueAsStruct, nestedOpts)
 			if iErr != nil {
 				err = iErr
 				return
@@ -70,52 +123,62 @@ func Apply(target interface{}, patch map[string]interface{}) (changed bool, err
 			continue
 		}
 
-		if !reflect.DeepEqual(value, dstValue) {
+		if dstKind != reflect.Map && dstKind != reflect.Slice && !reflect.DeepEqual(value, dstValue) {
 			changed = true
 		}
 
-		// handling of setting arrays/slices
-		if dstKind == reflect.Slice {
-			dstElemType := reflect.TypeOf(dstValue).Elem()
-			castedArray := reflect.MakeSlice(reflect.TypeOf(dstValue), srcValue.Len(), srcValue.Len())
-			valueAsArray, ok := value.([]interface{})
-			if !ok {
-				err = fmt.Errorf("%v is not an array", name)
+		// handling of map-typed fields (e.g. Labels map[string]string)
+		if dstKind == reflect.Map {
+			mapPatch, isMap := value.(map[string]interface{})
+			if !isMap {
+				err = fmt.Errorf("%v is not a map", name)
 				return
 			}
-			for i, srcElemValue := range valueAsArray {
-				valueToApply, isStruct := srcElemValue.(map[string]interface{})
-				if isStruct {
-					if dstElemType.Kind() == reflect.Pointer {
-						dstElemType = dstElemType.Elem()
-					}
-					newArrayElem := reflect.New(dstElemType)
-					elem := newArrayElem.Interface()
-					_, err = Apply(elem, valueToApply)
-					if err != nil {
-						return
-					}
-					castedArray.Index(i).Set(newArrayElem)
-				} else {
-					// simple values
-					reflectSrcElemValue := reflect.ValueOf(srcElemValue)
-					if !reflectSrcElemValue.CanConvert(dstElemType) {
-						err = fmt.Errorf("can't convert %v to dst type", name)
-						break
+			dstMapType := reflect.TypeOf(dstValue)
+			result := reflect.MakeMap(dstMapType)
+			for _, k := range reflect.ValueOf(dstValue).MapKeys() {
+				result.SetMapIndex(k, reflect.ValueOf(dstValue).MapIndex(k))
+			}
+			for mk, mv := range mapPatch {
+				mapKey, keyErr := convertMapKey(mk, dstMapType.Key())
+				if keyErr != nil {
+					err = fmt.Errorf("%v.%v: %w", name, mk, keyErr)
+					return
+				}
+				if mv == nil {
+					// under merge-patch semantics a null entry deletes the key;
+					// otherwise it's skipped, leaving the existing value in place
+					if nestedOpts.MergePatch {
+						result.SetMapIndex(mapKey, reflect.Value{})
 					}
-					castedArray.Index(i).Set(reflectSrcElemValue.Convert(dstElemType))
+					continue
 				}
-
+				var converted reflect.Value
+				converted, err = convertJSONValue(mv, dstMapType.Elem())
+				if err != nil {
+					err = fmt.Errorf("%v.%v: %w", name, mk, err)
+					return
+				}
+				result.SetMapIndex(mapKey, converted)
 			}
-			if err != nil {
+			if !reflect.DeepEqual(dstValue, result.Interface()) {
+				changed = true
+			}
+			if err = dstField.Set(result.Interface()); err != nil {
 				return
 			}
-			err = dstField.Set(castedArray.Interface())
+			continue
+		}
+
+		// handling of setting arrays/slices, per the field's patch:"..." merge strategy
+		if dstKind == reflect.Slice {
+			var sliceChanged bool
+			sliceChanged, err = applySliceField(dstField, name, value, nestedOpts)
 			if err != nil {
 				return
 			}
+			changed = changed || sliceChanged
 			continue
-
 		}
 
 		// other values
@@ -0,0 +1,291 @@
+package patch
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Diff walks oldValue and newValue, two values of the same struct type (or
+// pointers to structs), matching fields by json tag, and returns a patch map
+// for Apply holding only what changed. Nested structs produce nested maps;
+// slices and maps are emitted whole (as the []interface{}/map[string]interface{}
+// shape Apply expects) when any element differs; unexported fields, fields
+// tagged json:"-" and fields that are equal by reflect.DeepEqual are skipped.
+//
+// Fed back into Apply, the patch turns old into new for scalar fields,
+// pointer-to-struct fields and untagged slices, but not in every case: Apply
+// merges a map value into the existing map, so keys present only in old are
+// kept; fields of embedded structs are emitted at the parent level, where
+// Apply does not look them up; and Apply returns an error for a nested map
+// aimed at a non-pointer struct field.
+func Diff(oldValue, newValue interface{}) (map[string]interface{}, error) {
+	oldV, err := structValue(oldValue)
+	if err != nil {
+		return nil, fmt.Errorf("oldValue: %w", err)
+	}
+	newV, err := structValue(newValue)
+	if err != nil {
+		return nil, fmt.Errorf("newValue: %w", err)
+	}
+	if oldV.Type() != newV.Type() {
+		return nil, fmt.Errorf("oldValue and newValue must be the same type, got %s and %s", oldV.Type(), newV.Type())
+	}
+
+	out := make(map[string]interface{})
+	if err := diffFields(oldV, newV, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DiffJSONPatch is like Diff but returns the difference as an RFC 6902
+// operation list of "replace" operations, one per leaf that changed.
+func DiffJSONPatch(oldValue, newValue interface{}) ([]Operation, error) {
+	oldV, err := structValue(oldValue)
+	if err != nil {
+		return nil, fmt.Errorf("oldValue: %w", err)
+	}
+	newV, err := structValue(newValue)
+	if err != nil {
+		return nil, fmt.Errorf("newValue: %w", err)
+	}
+	if oldV.Type() != newV.Type() {
+		return nil, fmt.Errorf("oldValue and newValue must be the same type, got %s and %s", oldV.Type(), newV.Type())
+	}
+
+	var ops []Operation
+	if err := diffOps(oldV, newV, "", &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}, fmt.Errorf("got a nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("expected a struct or pointer to struct, got %s", rv.Kind())
+	}
+	return rv, nil
+}
+
+func diffFields(oldV, newV reflect.Value, out map[string]interface{}) error {
+	t := oldV.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+		oldFV, newFV := oldV.Field(i), newV.Field(i)
+
+		if sf.Anonymous {
+			oldEmbedded, newEmbedded, ok := dereferenceEmbedded(oldFV, newFV)
+			if ok {
+				if err := diffFields(oldEmbedded, newEmbedded, out); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		name, skip := jsonTagName(sf)
+		if skip {
+			continue
+		}
+
+		value, changed, err := diffValue(oldFV, newFV)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if changed {
+			out[name] = value
+		}
+	}
+	return nil
+}
+
+// diffValue compares a single pair of field values and returns the value to
+// put in the patch (a nil pointer of the field's type for deleted pointers)
+// plus whether they differ at all.
+func diffValue(oldFV, newFV reflect.Value) (interface{}, bool, error) {
+	switch oldFV.Kind() {
+	case reflect.Struct:
+		if isOpaqueStruct(oldFV.Type()) {
+			break
+		}
+		if reflect.DeepEqual(oldFV.Interface(), newFV.Interface()) {
+			return nil, false, nil
+		}
+		sub := make(map[string]interface{})
+		if err := diffFields(oldFV, newFV, sub); err != nil {
+			return nil, false, err
+		}
+		return sub, len(sub) > 0, nil
+	case reflect.Pointer:
+		if oldFV.Type().Elem().Kind() == reflect.Struct && !oldFV.IsNil() && !newFV.IsNil() && !isOpaqueStruct(oldFV.Type().Elem()) {
+			if reflect.DeepEqual(oldFV.Interface(), newFV.Interface()) {
+				return nil, false, nil
+			}
+			sub := make(map[string]interface{})
+			if err := diffFields(oldFV.Elem(), newFV.Elem(), sub); err != nil {
+				return nil, false, err
+			}
+			return sub, len(sub) > 0, nil
+		}
+	}
+
+	if reflect.DeepEqual(oldFV.Interface(), newFV.Interface()) {
+		return nil, false, nil
+	}
+
+	// Slices and maps must come back as the same []interface{}/
+	// map[string]interface{} shape json.Unmarshal would have produced:
+	// Apply's slice and map branches require that shape and reject a
+	// native typed slice/map outright.
+	switch oldFV.Kind() {
+	case reflect.Slice, reflect.Map:
+		shaped, err := toJSONShape(newFV.Interface())
+		if err != nil {
+			return nil, false, err
+		}
+		return shaped, true, nil
+	}
+	return newFV.Interface(), true, nil
+}
+
+// toJSONShape round-trips v through encoding/json so a native Go slice or
+// map comes back as the generic []interface{}/map[string]interface{} (and
+// nested scalars) json.Unmarshal would have produced, matching what Apply
+// and ApplyJSONPatch expect a patch value to look like.
+func toJSONShape(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func diffOps(oldV, newV reflect.Value, prefix string, ops *[]Operation) error {
+	t := oldV.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		oldFV, newFV := oldV.Field(i), newV.Field(i)
+
+		if sf.Anonymous {
+			oldEmbedded, newEmbedded, ok := dereferenceEmbedded(oldFV, newFV)
+			if ok {
+				if err := diffOps(oldEmbedded, newEmbedded, prefix, ops); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		name, skip := jsonTagName(sf)
+		if skip {
+			continue
+		}
+		path := prefix + "/" + escapePointerToken(name)
+
+		isStruct := oldFV.Kind() == reflect.Struct && !isOpaqueStruct(oldFV.Type())
+		isStructPtr := oldFV.Kind() == reflect.Pointer && oldFV.Type().Elem().Kind() == reflect.Struct &&
+			!oldFV.IsNil() && !newFV.IsNil() && !isOpaqueStruct(oldFV.Type().Elem())
+
+		if isStruct || isStructPtr {
+			if reflect.DeepEqual(oldFV.Interface(), newFV.Interface()) {
+				continue
+			}
+			sub1, sub2 := oldFV, newFV
+			if isStructPtr {
+				sub1, sub2 = oldFV.Elem(), newFV.Elem()
+			}
+			if err := diffOps(sub1, sub2, path, ops); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if reflect.DeepEqual(oldFV.Interface(), newFV.Interface()) {
+			continue
+		}
+
+		// A nil pointer/map/slice/interface comes out of newFV.Interface()
+		// as a typed nil wrapped in interface{} (e.g. (*Inner)(nil)), which
+		// setReflectValue's `value == nil` check doesn't recognize; emit a
+		// literal nil so ApplyJSONPatch actually zeroes the field.
+		var opValue interface{} = newFV.Interface()
+		switch newFV.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+			if newFV.IsNil() {
+				opValue = nil
+			}
+		}
+		*ops = append(*ops, Operation{Op: "replace", Path: path, Value: opValue})
+	}
+	return nil
+}
+
+// dereferenceEmbedded returns the embedded struct values to recurse into for
+// an anonymous field, or ok=false if it isn't an embedded struct (or pointer
+// to struct) that's present on both sides.
+func dereferenceEmbedded(oldFV, newFV reflect.Value) (reflect.Value, reflect.Value, bool) {
+	for oldFV.Kind() == reflect.Pointer {
+		if oldFV.IsNil() || newFV.IsNil() {
+			return reflect.Value{}, reflect.Value{}, false
+		}
+		oldFV, newFV = oldFV.Elem(), newFV.Elem()
+	}
+	if oldFV.Kind() != reflect.Struct {
+		return reflect.Value{}, reflect.Value{}, false
+	}
+	return oldFV, newFV, true
+}
+
+func jsonTagName(sf reflect.StructField) (name string, skip bool) {
+	tag, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if tag == "-" {
+		return "", true
+	}
+	if tag == "" {
+		return sf.Name, false
+	}
+	return tag, false
+}
+
+func isOpaqueStruct(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func escapePointerToken(tok string) string {
+	tok = strings.ReplaceAll(tok, "~", "~0")
+	tok = strings.ReplaceAll(tok, "/", "~1")
+	return tok
+}
@@ -0,0 +1,150 @@
+package patch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffDetectsScalarChanges(t *testing.T) {
+	type Target struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Salary    int    `json:"salary"`
+	}
+	oldValue := Target{FirstName: "Anakin", LastName: "Skywalker", Salary: 123}
+	newValue := Target{FirstName: "Darth", LastName: "Skywalker", Salary: 100500}
+
+	d, err := Diff(oldValue, newValue)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"first_name": "Darth", "salary": 100500}, d)
+}
+
+func TestDiffIgnoresUnexportedFields(t *testing.T) {
+	type Target struct {
+		Exported   string `json:"exported"`
+		unexported string
+	}
+	oldValue := Target{Exported: "stormtrooper", unexported: "private"}
+	newValue := Target{Exported: "stormtrooper", unexported: "leutenant"}
+
+	d, err := Diff(oldValue, newValue)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{}, d)
+}
+
+func TestDiffNestedStructsProduceNestedMaps(t *testing.T) {
+	type Contact struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	}
+	type Target struct {
+		Contact *Contact `json:"contact"`
+		Salary  int      `json:"salary"`
+	}
+	oldValue := Target{Contact: &Contact{FirstName: "Anakin", LastName: "Skywalker"}, Salary: 123}
+	newValue := Target{Contact: &Contact{FirstName: "Darth", LastName: "Skywalker"}, Salary: 123}
+
+	d, err := Diff(oldValue, newValue)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"contact": map[string]interface{}{"first_name": "Darth"},
+	}, d)
+}
+
+func TestDiffAppliesBackOntoOld(t *testing.T) {
+	type Target struct {
+		FirstName string `json:"first_name"`
+		Salary    int    `json:"salary"`
+	}
+	oldValue := Target{FirstName: "Anakin", Salary: 123}
+	newValue := Target{FirstName: "Darth", Salary: 100500}
+
+	d, err := Diff(oldValue, newValue)
+	assert.NoError(t, err)
+
+	_, err = Apply(&oldValue, d)
+	assert.NoError(t, err)
+	assert.Equal(t, newValue, oldValue)
+}
+
+func TestDiffEmitsWholeSliceWhenDifferent(t *testing.T) {
+	type Target struct {
+		Characters []string `json:"characters"`
+	}
+	oldValue := Target{Characters: []string{"Luke"}}
+	newValue := Target{Characters: []string{"Luke", "Leia"}}
+
+	d, err := Diff(oldValue, newValue)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"characters": []interface{}{"Luke", "Leia"}}, d)
+
+	_, err = Apply(&oldValue, d)
+	assert.NoError(t, err)
+	assert.Equal(t, newValue, oldValue)
+}
+
+func TestDiffEmitsMapShapeApplyAccepts(t *testing.T) {
+	type Target struct {
+		Labels map[string]string `json:"labels"`
+	}
+	oldValue := Target{Labels: map[string]string{"team": "jedi"}}
+	newValue := Target{Labels: map[string]string{"team": "sith"}}
+
+	d, err := Diff(oldValue, newValue)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"labels": map[string]interface{}{"team": "sith"}}, d)
+
+	_, err = Apply(&oldValue, d)
+	assert.NoError(t, err)
+	assert.Equal(t, newValue, oldValue)
+}
+
+func TestDiffTreatsTimeAsScalar(t *testing.T) {
+	type Target struct {
+		CreatedAt time.Time `json:"created_at"`
+	}
+	base := time.Date(2016, 1, 2, 15, 4, 5, 0, time.UTC)
+	oldValue := Target{CreatedAt: base}
+	newValue := Target{CreatedAt: base.Add(time.Hour)}
+
+	d, err := Diff(oldValue, newValue)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"created_at": newValue.CreatedAt}, d)
+}
+
+func TestDiffJSONPatchProducesReplaceOps(t *testing.T) {
+	type Contact struct {
+		FirstName string `json:"first_name"`
+	}
+	type Target struct {
+		Contact *Contact `json:"contact"`
+		Salary  int      `json:"salary"`
+	}
+	oldValue := Target{Contact: &Contact{FirstName: "Anakin"}, Salary: 123}
+	newValue := Target{Contact: &Contact{FirstName: "Darth"}, Salary: 123}
+
+	ops, err := DiffJSONPatch(oldValue, newValue)
+	assert.NoError(t, err)
+	assert.Equal(t, []Operation{{Op: "replace", Path: "/contact/first_name", Value: "Darth"}}, ops)
+}
+
+func TestDiffJSONPatchNilsOutPointerField(t *testing.T) {
+	type Contact struct {
+		FirstName string `json:"first_name"`
+	}
+	type Target struct {
+		Contact *Contact `json:"contact"`
+	}
+	oldValue := Target{Contact: &Contact{FirstName: "Anakin"}}
+	newValue := Target{Contact: nil}
+
+	ops, err := DiffJSONPatch(oldValue, newValue)
+	assert.NoError(t, err)
+	assert.Equal(t, []Operation{{Op: "replace", Path: "/contact", Value: nil}}, ops)
+
+	_, err = ApplyJSONPatch(&oldValue, ops)
+	assert.NoError(t, err)
+	assert.Equal(t, newValue, oldValue)
+}
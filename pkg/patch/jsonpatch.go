@@ -0,0 +1,520 @@
+package patch
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Operation is a single RFC 6902 JSON Patch operation. Path and From are
+// JSON Pointers (RFC 6901) resolved against the target using the same
+// json-tag lookup Apply uses.
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies a sequence of RFC 6902 operations to target, which
+// must be a pointer to a struct. Supported ops are add, remove, replace,
+// move, copy and test.
+//
+// The batch is transactional: if any operation fails, target is left exactly
+// as it was before the call.
+func ApplyJSONPatch(target interface{}, ops []Operation) (changed bool, err error) {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return false, fmt.Errorf("target must be a pointer to a struct")
+	}
+
+	root := rv.Elem()
+	backup := reflect.New(root.Type())
+	backup.Elem().Set(deepCopyValue(root))
+
+	for _, op := range ops {
+		var opChanged bool
+		opChanged, err = applyOperation(root, op)
+		if err != nil {
+			root.Set(backup.Elem())
+			return false, err
+		}
+		changed = changed || opChanged
+	}
+	return changed, nil
+}
+
+func applyOperation(root reflect.Value, op Operation) (bool, error) {
+	tokens, err := parsePointer(op.Path)
+	if err != nil {
+		return false, err
+	}
+
+	switch op.Op {
+	case "add":
+		return true, setAt(root, tokens, op.Value, true)
+	case "remove":
+		if _, ok := getAt(root, tokens); !ok {
+			return false, fmt.Errorf("remove: path %q does not exist", op.Path)
+		}
+		return true, removeAt(root, tokens)
+	case "replace":
+		if _, ok := getAt(root, tokens); !ok {
+			return false, fmt.Errorf("replace: path %q does not exist", op.Path)
+		}
+		return true, setAt(root, tokens, op.Value, false)
+	case "move":
+		fromTokens, err := parsePointer(op.From)
+		if err != nil {
+			return false, err
+		}
+		v, ok := getAt(root, fromTokens)
+		if !ok {
+			return false, fmt.Errorf("move: source path %q does not exist", op.From)
+		}
+		value := v.Interface()
+		if err := removeAt(root, fromTokens); err != nil {
+			return false, err
+		}
+		return true, setAt(root, tokens, value, true)
+	case "copy":
+		fromTokens, err := parsePointer(op.From)
+		if err != nil {
+			return false, err
+		}
+		v, ok := getAt(root, fromTokens)
+		if !ok {
+			return false, fmt.Errorf("copy: source path %q does not exist", op.From)
+		}
+		return true, setAt(root, tokens, v.Interface(), true)
+	case "test":
+		v, ok := getAt(root, tokens)
+		if !ok {
+			return false, fmt.Errorf("test: path %q does not exist", op.Path)
+		}
+		expected, err := convertJSONValue(op.Value, v.Type())
+		if err != nil || !reflect.DeepEqual(expected.Interface(), v.Interface()) {
+			return false, fmt.Errorf("test: path %q does not match expected value", op.Path)
+		}
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown op %q", op.Op)
+	}
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its decoded reference
+// tokens, e.g. "/contact/first_name" -> ["contact", "first_name"].
+func parsePointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty JSON pointer is not supported")
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with '/'", path)
+	}
+	raw := strings.Split(path[1:], "/")
+	tokens := make([]string, len(raw))
+	for i, t := range raw {
+		t = strings.ReplaceAll(t, "~1", "/")
+		t = strings.ReplaceAll(t, "~0", "~")
+		tokens[i] = t
+	}
+	return tokens, nil
+}
+
+// fieldByJSONTag finds the field of struct value v whose json tag (or Go
+// name, if untagged) matches name, as findField does for Apply's map-shaped
+// patches. Unlike findField, it also descends into embedded structs.
+func fieldByJSONTag(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+		if sf.Anonymous {
+			fv := v.Field(i)
+			for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if found, ok := fieldByJSONTag(fv, name); ok {
+					return found, true
+				}
+			}
+			continue
+		}
+		tag, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if tag == "" {
+			tag = sf.Name
+		}
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// sliceIndex parses a JSON Pointer array token into an index into a slice of
+// the given length. allowAppend accepts both "-" and the one-past-the-end
+// index, as used by the "add" operation.
+func sliceIndex(tok string, length int, allowAppend bool) (int, error) {
+	if tok == "-" {
+		if !allowAppend {
+			return 0, fmt.Errorf(`index "-" is only valid when appending`)
+		}
+		return length, nil
+	}
+	idx, err := strconv.Atoi(tok)
+	if err != nil || idx < 0 || idx > length || (idx == length && !allowAppend) {
+		return 0, fmt.Errorf("invalid slice index %q", tok)
+	}
+	return idx, nil
+}
+
+// locate walks tokens against v and returns the container that holds the
+// final token (a struct, map or slice) together with that token, so callers
+// can get/set/remove at that position. Maps are copied out into an
+// addressable value so nested mutation can recurse through them; writeback
+// commits those copies back into their parent maps once the caller is done.
+func locate(v reflect.Value, tokens []string, autoAlloc bool) (parent reflect.Value, last string, writeback func(), err error) {
+	if len(tokens) == 0 {
+		return reflect.Value{}, "", nil, fmt.Errorf("empty path")
+	}
+
+	var writebacks []func()
+	deref := func(tok string) error {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !autoAlloc || !v.CanSet() {
+					return fmt.Errorf("nil pointer at token %q", tok)
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		return nil
+	}
+
+	for i := 0; i < len(tokens)-1; i++ {
+		tok := tokens[i]
+		if err := deref(tok); err != nil {
+			return reflect.Value{}, "", nil, err
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			fv, ok := fieldByJSONTag(v, tok)
+			if !ok {
+				return reflect.Value{}, "", nil, fmt.Errorf("unknown field %q", tok)
+			}
+			v = fv
+		case reflect.Map:
+			mapValue := v
+			key, err := convertMapKey(tok, v.Type().Key())
+			if err != nil {
+				return reflect.Value{}, "", nil, err
+			}
+			elem := v.MapIndex(key)
+			if !elem.IsValid() {
+				if !autoAlloc {
+					return reflect.Value{}, "", nil, fmt.Errorf("unknown map key %q", tok)
+				}
+				elem = reflect.Zero(v.Type().Elem())
+			}
+			addr := reflect.New(v.Type().Elem()).Elem()
+			addr.Set(elem)
+			writebacks = append(writebacks, func() { mapValue.SetMapIndex(key, addr) })
+			v = addr
+		case reflect.Slice:
+			idx, err := sliceIndex(tok, v.Len(), false)
+			if err != nil {
+				return reflect.Value{}, "", nil, err
+			}
+			v = v.Index(idx)
+		default:
+			return reflect.Value{}, "", nil, fmt.Errorf("cannot descend into %s at token %q", v.Kind(), tok)
+		}
+	}
+	if err := deref(tokens[len(tokens)-1]); err != nil {
+		return reflect.Value{}, "", nil, err
+	}
+
+	wb := writebacks
+	return v, tokens[len(tokens)-1], func() {
+		for i := len(wb) - 1; i >= 0; i-- {
+			wb[i]()
+		}
+	}, nil
+}
+
+func getAt(root reflect.Value, tokens []string) (reflect.Value, bool) {
+	parent, last, _, err := locate(root, tokens, false)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	switch parent.Kind() {
+	case reflect.Struct:
+		return fieldByJSONTag(parent, last)
+	case reflect.Map:
+		key, err := convertMapKey(last, parent.Type().Key())
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		v := parent.MapIndex(key)
+		return v, v.IsValid()
+	case reflect.Slice:
+		idx, err := sliceIndex(last, parent.Len(), false)
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		return parent.Index(idx), true
+	}
+	return reflect.Value{}, false
+}
+
+func setAt(root reflect.Value, tokens []string, value interface{}, insert bool) error {
+	parent, last, writeback, err := locate(root, tokens, true)
+	if err != nil {
+		return err
+	}
+	defer writeback()
+
+	switch parent.Kind() {
+	case reflect.Struct:
+		fv, ok := fieldByJSONTag(parent, last)
+		if !ok {
+			return fmt.Errorf("unknown field %q", last)
+		}
+		return setReflectValue(fv, value)
+	case reflect.Map:
+		key, err := convertMapKey(last, parent.Type().Key())
+		if err != nil {
+			return err
+		}
+		converted, err := convertJSONValue(value, parent.Type().Elem())
+		if err != nil {
+			return err
+		}
+		parent.SetMapIndex(key, converted)
+		return nil
+	case reflect.Slice:
+		idx, err := sliceIndex(last, parent.Len(), insert)
+		if err != nil {
+			return err
+		}
+		converted, err := convertJSONValue(value, parent.Type().Elem())
+		if err != nil {
+			return err
+		}
+		if !insert {
+			parent.Index(idx).Set(converted)
+			return nil
+		}
+		if !parent.CanSet() {
+			return fmt.Errorf("cannot grow slice at %q", last)
+		}
+		grown := reflect.Append(parent, reflect.Zero(parent.Type().Elem()))
+		reflect.Copy(grown.Slice(idx+1, grown.Len()), grown.Slice(idx, grown.Len()-1))
+		grown.Index(idx).Set(converted)
+		parent.Set(grown)
+		return nil
+	}
+	return fmt.Errorf("cannot set a value inside %s", parent.Kind())
+}
+
+func removeAt(root reflect.Value, tokens []string) error {
+	parent, last, writeback, err := locate(root, tokens, false)
+	if err != nil {
+		return err
+	}
+	defer writeback()
+
+	switch parent.Kind() {
+	case reflect.Struct:
+		fv, ok := fieldByJSONTag(parent, last)
+		if !ok {
+			return fmt.Errorf("unknown field %q", last)
+		}
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	case reflect.Map:
+		key, err := convertMapKey(last, parent.Type().Key())
+		if err != nil {
+			return err
+		}
+		if !parent.MapIndex(key).IsValid() {
+			return fmt.Errorf("unknown map key %q", last)
+		}
+		parent.SetMapIndex(key, reflect.Value{})
+		return nil
+	case reflect.Slice:
+		idx, err := sliceIndex(last, parent.Len(), false)
+		if err != nil {
+			return err
+		}
+		if !parent.CanSet() {
+			return fmt.Errorf("cannot remove from slice at %q", last)
+		}
+		reflect.Copy(parent.Slice(idx, parent.Len()), parent.Slice(idx+1, parent.Len()))
+		parent.Set(parent.Slice(0, parent.Len()-1))
+		return nil
+	}
+	return fmt.Errorf("cannot remove a value inside %s", parent.Kind())
+}
+
+// setReflectValue assigns value into the addressable field fv, recursing
+// through Apply for object values targeting a struct (or pointer to struct)
+// so partial updates and nested merges behave the same as they do today.
+func setReflectValue(fv reflect.Value, value interface{}) error {
+	if value == nil {
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	}
+
+	isStructDest := fv.Kind() == reflect.Struct ||
+		(fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.Struct)
+	if m, ok := value.(map[string]interface{}); ok && isStructDest {
+		if fv.Kind() == reflect.Pointer && fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		target := fv.Addr().Interface()
+		if fv.Kind() == reflect.Pointer {
+			target = fv.Interface()
+		}
+		_, err := Apply(target, m)
+		return err
+	}
+
+	converted, err := convertJSONValue(value, fv.Type())
+	if err != nil {
+		return err
+	}
+	fv.Set(converted)
+	return nil
+}
+
+// convertJSONValue converts a decoded JSON value (string, float64, bool,
+// map[string]interface{}, []interface{} or nil) into destType, recursing
+// into Apply for nested structs.
+func convertJSONValue(value interface{}, destType reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(destType), nil
+	}
+	if unmarshaled, handled, err := unmarshalScalar(destType, value); handled {
+		return unmarshaled, err
+	}
+	if destType.Kind() == reflect.Pointer {
+		inner, err := convertJSONValue(value, destType.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(destType.Elem())
+		ptr.Elem().Set(inner)
+		return ptr, nil
+	}
+	if m, ok := value.(map[string]interface{}); ok {
+		if destType.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("cannot assign an object to %s", destType)
+		}
+		dest := reflect.New(destType)
+		if _, err := Apply(dest.Interface(), m); err != nil {
+			return reflect.Value{}, err
+		}
+		return dest.Elem(), nil
+	}
+	if arr, ok := value.([]interface{}); ok {
+		if destType.Kind() != reflect.Slice {
+			return reflect.Value{}, fmt.Errorf("cannot assign an array to %s", destType)
+		}
+		out := reflect.MakeSlice(destType, len(arr), len(arr))
+		for i, e := range arr {
+			ev, err := convertJSONValue(e, destType.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.Index(i).Set(ev)
+		}
+		return out, nil
+	}
+	rv := reflect.ValueOf(value)
+	if !rv.CanConvert(destType) {
+		return reflect.Value{}, fmt.Errorf("can't convert %v to %s", value, destType)
+	}
+	return rv.Convert(destType), nil
+}
+
+// convertMapKey converts a JSON Pointer token (or a patch map key, which is
+// always a string) into keyType, returning an error instead of panicking
+// when the destination map's key kind can't hold it (e.g. keyType is int).
+func convertMapKey(tok string, keyType reflect.Type) (reflect.Value, error) {
+	rv := reflect.ValueOf(tok)
+	if !rv.CanConvert(keyType) {
+		return reflect.Value{}, fmt.Errorf("map key %q cannot be converted to %s", tok, keyType)
+	}
+	return rv.Convert(keyType), nil
+}
+
+// deepCopyValue returns an independent copy of v, recursing through
+// pointers, slices, maps and struct fields so that mutating the result can
+// never reach back into v's backing memory. Used to snapshot the target
+// before a transactional batch so a rollback is a real rollback, not just a
+// copy of the top-level struct header.
+func deepCopyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(deepCopyValue(v.Elem()))
+		return out
+	case reflect.Struct:
+		// Shallow-copy first so unexported fields (which can't be Set
+		// individually, e.g. time.Time's internals) still come along;
+		// then deep-copy exported fields over top so their own pointer,
+		// slice and map backing memory is independent of v's.
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := out.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			field.Set(deepCopyValue(v.Field(i)))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, k := range v.MapKeys() {
+			out.SetMapIndex(k, deepCopyValue(v.MapIndex(k)))
+		}
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(deepCopyValue(v.Elem()))
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,178 @@
+package patch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONPatchReplaceAndAdd(t *testing.T) {
+	type Target struct {
+		FirstName string `json:"first_name"`
+		Salary    int    `json:"salary"`
+	}
+	a := Target{FirstName: "Anakin", Salary: 123}
+
+	chg, err := ApplyJSONPatch(&a, []Operation{
+		{Op: "replace", Path: "/first_name", Value: "Darth"},
+		{Op: "add", Path: "/salary", Value: 100500},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, "Darth", a.FirstName)
+	assert.Equal(t, 100500, a.Salary)
+}
+
+func TestJSONPatchAddAllocatesNestedPointer(t *testing.T) {
+	type Contact struct {
+		FirstName string `json:"first_name"`
+	}
+	type Target struct {
+		Contact *Contact `json:"contact"`
+	}
+	a := Target{}
+
+	chg, err := ApplyJSONPatch(&a, []Operation{
+		{Op: "add", Path: "/contact/first_name", Value: "Luke"},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, "Luke", a.Contact.FirstName)
+}
+
+func TestJSONPatchSliceInsertAndRemove(t *testing.T) {
+	type Target struct {
+		Characters []string `json:"characters"`
+	}
+	a := Target{Characters: []string{"Luke", "Han"}}
+
+	chg, err := ApplyJSONPatch(&a, []Operation{
+		{Op: "add", Path: "/characters/1", Value: "Leia"},
+		{Op: "remove", Path: "/characters/0"},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, []string{"Leia", "Han"}, a.Characters)
+}
+
+func TestJSONPatchAppendWithDash(t *testing.T) {
+	type Target struct {
+		Characters []string `json:"characters"`
+	}
+	a := Target{Characters: []string{"Luke"}}
+
+	_, err := ApplyJSONPatch(&a, []Operation{
+		{Op: "add", Path: "/characters/-", Value: "Leia"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Luke", "Leia"}, a.Characters)
+}
+
+func TestJSONPatchMoveAndCopy(t *testing.T) {
+	type Target struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	}
+	a := Target{FirstName: "Anakin"}
+
+	chg, err := ApplyJSONPatch(&a, []Operation{
+		{Op: "copy", From: "/first_name", Path: "/last_name"},
+		{Op: "move", From: "/last_name", Path: "/first_name"},
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, "Anakin", a.FirstName)
+	assert.Equal(t, "", a.LastName)
+}
+
+func TestJSONPatchTest(t *testing.T) {
+	type Target struct {
+		Salary int `json:"salary"`
+	}
+	a := Target{Salary: 123}
+
+	_, err := ApplyJSONPatch(&a, []Operation{
+		{Op: "test", Path: "/salary", Value: float64(123)},
+	})
+	assert.NoError(t, err)
+
+	_, err = ApplyJSONPatch(&a, []Operation{
+		{Op: "test", Path: "/salary", Value: float64(999)},
+	})
+	assert.Error(t, err)
+}
+
+func TestJSONPatchRollsBackOnFailure(t *testing.T) {
+	type Target struct {
+		FirstName string `json:"first_name"`
+		Salary    int    `json:"salary"`
+	}
+	a := Target{FirstName: "Anakin", Salary: 123}
+
+	_, err := ApplyJSONPatch(&a, []Operation{
+		{Op: "replace", Path: "/first_name", Value: "Darth"},
+		{Op: "replace", Path: "/does_not_exist", Value: "oops"},
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, "Anakin", a.FirstName)
+	assert.Equal(t, 123, a.Salary)
+}
+
+func TestJSONPatchRollsBackNestedPointerAndSliceMutations(t *testing.T) {
+	type Inner struct {
+		Name string `json:"name"`
+	}
+	type Target struct {
+		Inner *Inner   `json:"inner"`
+		Tags  []string `json:"tags"`
+	}
+	a := Target{Inner: &Inner{Name: "original"}, Tags: []string{"a", "b"}}
+
+	_, err := ApplyJSONPatch(&a, []Operation{
+		{Op: "replace", Path: "/inner/name", Value: "mutated"},
+		{Op: "replace", Path: "/tags/0", Value: "mutated"},
+		{Op: "replace", Path: "/does_not_exist", Value: "oops"},
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, "original", a.Inner.Name)
+	assert.Equal(t, []string{"a", "b"}, a.Tags)
+}
+
+func TestJSONPatchRollbackPreservesUnexportedFields(t *testing.T) {
+	type Target struct {
+		CreatedAt time.Time `json:"created_at"`
+		Name      string    `json:"name"`
+	}
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := Target{CreatedAt: created, Name: "Anakin"}
+
+	_, err := ApplyJSONPatch(&a, []Operation{
+		{Op: "replace", Path: "/name", Value: "Darth"},
+		{Op: "replace", Path: "/does_not_exist", Value: "oops"},
+	})
+
+	assert.Error(t, err)
+	assert.True(t, created.Equal(a.CreatedAt))
+	assert.Equal(t, "Anakin", a.Name)
+}
+
+func TestJSONPatchReturnsErrorForNonConvertibleMapKey(t *testing.T) {
+	type Target struct {
+		Counts map[int]string `json:"counts"`
+	}
+	a := Target{Counts: map[int]string{}}
+
+	_, err := ApplyJSONPatch(&a, []Operation{
+		{Op: "add", Path: "/counts/1", Value: "one"},
+	})
+
+	assert.Error(t, err)
+}
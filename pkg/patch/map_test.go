@@ -0,0 +1,86 @@
+package patch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMergesMapFields(t *testing.T) {
+	type Target struct {
+		Labels map[string]string `json:"labels"`
+	}
+	a := Target{Labels: map[string]string{"team": "jedi", "rank": "padawan"}}
+
+	data := `{"labels": {"rank": "knight", "planet": "tatooine"}}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, map[string]string{"team": "jedi", "rank": "knight", "planet": "tatooine"}, a.Labels)
+}
+
+func TestApplyMapIntValues(t *testing.T) {
+	type Target struct {
+		Annotations map[string]int `json:"annotations"`
+	}
+	a := Target{}
+
+	data := `{"annotations": {"age": 19}}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, map[string]int{"age": 19}, a.Annotations)
+}
+
+func TestApplySkipsNullMapEntryOutsideMergePatch(t *testing.T) {
+	type Target struct {
+		Labels map[string]string `json:"labels"`
+	}
+	a := Target{Labels: map[string]string{"team": "jedi"}}
+
+	data := `{"labels": {"team": null}}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.False(t, chg)
+	assert.Equal(t, map[string]string{"team": "jedi"}, a.Labels)
+}
+
+func TestMergePatchDeletesMapEntry(t *testing.T) {
+	type Target struct {
+		Labels map[string]string `json:"labels"`
+	}
+	a := Target{Labels: map[string]string{"team": "jedi", "rank": "padawan"}}
+
+	data := `{"labels": {"team": null}}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := ApplyMergePatch(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, map[string]string{"rank": "padawan"}, a.Labels)
+}
+
+func TestApplyReturnsErrorForNonConvertibleMapKey(t *testing.T) {
+	type Target struct {
+		Counts map[int]string `json:"counts"`
+	}
+	a := Target{Counts: map[int]string{}}
+
+	data := `{"counts": {"1": "one"}}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	_, err := Apply(&a, p)
+	assert.Error(t, err)
+}
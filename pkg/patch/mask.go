@@ -0,0 +1,100 @@
+package patch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldFilter decides which fields a field mask allows Apply to touch.
+// Filter reports whether name is allowed at this level and, if it is, the
+// FieldFilter to apply to its nested fields (nil means everything beneath it
+// is allowed).
+type FieldFilter interface {
+	Filter(name string) (subFilter FieldFilter, allowed bool)
+}
+
+// maskNode is the FieldFilter built by ParseMask: a set of allowed field
+// names, each optionally scoped to its own nested mask.
+type maskNode struct {
+	children map[string]*maskNode
+}
+
+func (n *maskNode) Filter(name string) (FieldFilter, bool) {
+	child, ok := n.children[name]
+	if !ok {
+		return nil, false
+	}
+	if child == nil || len(child.children) == 0 {
+		return nil, true
+	}
+	return child, true
+}
+
+// ApplyWithMask behaves like Apply but only touches fields allowed by mask;
+// everything else is silently skipped. Use ApplyWithOptions with
+// Options{Mask: mask, StrictMask: true} instead to turn disallowed fields
+// into an error.
+func ApplyWithMask(target interface{}, patch map[string]interface{}, mask FieldFilter) (changed bool, err error) {
+	return ApplyWithOptions(target, patch, Options{Mask: mask})
+}
+
+// ParseMask builds a FieldFilter tree from a gRPC-style field mask, e.g.
+// "first_name,contact{first_name,last_name},characters". A field followed
+// by "{...}" scopes the mask to its nested fields; a field without braces
+// allows its entire subtree.
+func ParseMask(mask string) (FieldFilter, error) {
+	node, rest, err := parseMaskNode(mask)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(rest) != "" {
+		return nil, fmt.Errorf("unexpected trailing input %q in field mask", rest)
+	}
+	return node, nil
+}
+
+// parseMaskNode parses a comma-separated field list (the contents of the
+// top-level mask, or of a {...} group) until it hits an unmatched '}' or the
+// end of input, and returns whatever input is left unparsed.
+func parseMaskNode(s string) (*maskNode, string, error) {
+	node := &maskNode{children: map[string]*maskNode{}}
+	for {
+		s = strings.TrimSpace(s)
+		if s == "" || s[0] == '}' {
+			return node, s, nil
+		}
+
+		end := strings.IndexAny(s, ",{}")
+		var name string
+		if end == -1 {
+			name, s = s, ""
+		} else {
+			name, s = s[:end], s[end:]
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, "", fmt.Errorf("empty field name in mask %q", s)
+		}
+
+		var child *maskNode
+		if strings.HasPrefix(s, "{") {
+			var err error
+			child, s, err = parseMaskNode(s[1:])
+			if err != nil {
+				return nil, "", err
+			}
+			if !strings.HasPrefix(s, "}") {
+				return nil, "", fmt.Errorf("missing closing '}' for field %q", name)
+			}
+			s = s[1:]
+		}
+		node.children[name] = child
+
+		s = strings.TrimSpace(s)
+		if strings.HasPrefix(s, ",") {
+			s = s[1:]
+			continue
+		}
+		return node, s, nil
+	}
+}
@@ -0,0 +1,97 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMaskBuildsTree(t *testing.T) {
+	mask, err := ParseMask("first_name,contact{first_name,last_name},characters")
+	assert.NoError(t, err)
+
+	node, ok := mask.Filter("first_name")
+	assert.True(t, ok)
+	assert.Nil(t, node)
+
+	sub, ok := mask.Filter("contact")
+	assert.True(t, ok)
+	assert.NotNil(t, sub)
+	_, ok = sub.Filter("first_name")
+	assert.True(t, ok)
+	_, ok = sub.Filter("position")
+	assert.False(t, ok)
+
+	_, ok = mask.Filter("salary")
+	assert.False(t, ok)
+}
+
+func TestParseMaskRejectsUnbalancedBraces(t *testing.T) {
+	_, err := ParseMask("contact{first_name")
+	assert.Error(t, err)
+}
+
+func TestApplyWithMaskSkipsDisallowedFields(t *testing.T) {
+	type Target struct {
+		FirstName string `json:"first_name"`
+		Salary    int    `json:"salary"`
+	}
+	a := Target{FirstName: "Anakin", Salary: 123}
+
+	mask, err := ParseMask("first_name")
+	assert.NoError(t, err)
+
+	chg, err := ApplyWithMask(&a, map[string]interface{}{
+		"first_name": "Darth",
+		"salary":     999,
+	}, mask)
+
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, "Darth", a.FirstName)
+	assert.Equal(t, 123, a.Salary) // masked out
+}
+
+func TestApplyWithMaskScopesNestedFields(t *testing.T) {
+	type Contact struct {
+		FirstName string `json:"first_name"`
+		Position  string `json:"position"`
+	}
+	type Target struct {
+		Contact *Contact `json:"contact"`
+	}
+	a := Target{Contact: &Contact{FirstName: "Anakin", Position: "padawan"}}
+
+	mask, err := ParseMask("contact{first_name}")
+	assert.NoError(t, err)
+
+	chg, err := ApplyWithMask(&a, map[string]interface{}{
+		"contact": map[string]interface{}{
+			"first_name": "Darth",
+			"position":   "sith",
+		},
+	}, mask)
+
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, "Darth", a.Contact.FirstName)
+	assert.Equal(t, "padawan", a.Contact.Position) // masked out
+}
+
+func TestApplyWithOptionsStrictMaskErrors(t *testing.T) {
+	type Target struct {
+		FirstName string `json:"first_name"`
+		Salary    int    `json:"salary"`
+	}
+	a := Target{FirstName: "Anakin", Salary: 123}
+
+	mask, err := ParseMask("first_name")
+	assert.NoError(t, err)
+
+	_, err = ApplyWithOptions(&a, map[string]interface{}{
+		"salary": 999,
+	}, Options{Mask: mask, StrictMask: true})
+
+	assert.Error(t, err)
+	assert.Equal(t, 123, a.Salary)
+}
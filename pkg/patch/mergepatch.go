@@ -0,0 +1,15 @@
+package patch
+
+// ApplyMergePatch applies patch to target following RFC 7396 JSON Merge
+// Patch semantics: objects are merged recursively as Apply already does,
+// arrays are replaced wholesale unless the slice field's `patch:"..."` tag
+// (or Options.DefaultSliceStrategy) selects another strategy, and a null
+// value deletes the corresponding
+// field instead of being skipped — zeroing a scalar, nilling a pointer, or
+// clearing a slice/map.
+//
+// Returns true if any value has been changed. A null that targets an
+// already-zero field does not count as a change.
+func ApplyMergePatch(target interface{}, patch map[string]interface{}) (changed bool, err error) {
+	return ApplyWithOptions(target, patch, Options{MergePatch: true})
+}
@@ -0,0 +1,98 @@
+package patch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatchNullDeletesScalar(t *testing.T) {
+	type Target struct {
+		FirstName string `json:"first_name"`
+		Salary    int    `json:"salary"`
+	}
+	a := Target{FirstName: "Anakin", Salary: 123}
+
+	data := `{"salary": null}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := ApplyMergePatch(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, "Anakin", a.FirstName)
+	assert.Equal(t, 0, a.Salary)
+}
+
+func TestMergePatchNullDeletesPointer(t *testing.T) {
+	type Contact struct {
+		FirstName string `json:"first_name"`
+	}
+	type Target struct {
+		Contact *Contact `json:"contact"`
+	}
+	a := Target{Contact: &Contact{FirstName: "Anakin"}}
+
+	data := `{"contact": null}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := ApplyMergePatch(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Nil(t, a.Contact)
+}
+
+func TestMergePatchNullOnZeroFieldIsNotAChange(t *testing.T) {
+	type Target struct {
+		Salary int `json:"salary"`
+	}
+	a := Target{Salary: 0}
+
+	data := `{"salary": null}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := ApplyMergePatch(&a, p)
+	assert.NoError(t, err)
+	assert.False(t, chg)
+	assert.Equal(t, 0, a.Salary)
+}
+
+func TestMergePatchNullRecursesIntoNestedObjects(t *testing.T) {
+	type Contact struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	}
+	type Target struct {
+		Contact *Contact `json:"contact"`
+	}
+	a := Target{Contact: &Contact{FirstName: "Anakin", LastName: "Skywalker"}}
+
+	data := `{"contact": {"first_name": null, "last_name": "Vader"}}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := ApplyMergePatch(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, "", a.Contact.FirstName)
+	assert.Equal(t, "Vader", a.Contact.LastName)
+}
+
+func TestApplyStillSkipsNull(t *testing.T) {
+	type Target struct {
+		Salary int `json:"salary"`
+	}
+	a := Target{Salary: 123}
+
+	data := `{"salary": null}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.False(t, chg)
+	assert.Equal(t, 123, a.Salary)
+}
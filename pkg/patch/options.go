@@ -0,0 +1,21 @@
+package patch
+
+// Options controls how ApplyWithOptions behaves beyond Apply's defaults.
+type Options struct {
+	// MergePatch switches to RFC 7396 JSON Merge Patch semantics: a null
+	// value deletes the target field (zeroes a scalar, nils a pointer,
+	// clears a slice/map) instead of being skipped.
+	MergePatch bool
+
+	// Mask, if set, restricts which fields Apply is allowed to touch.
+	// Fields it disallows are silently skipped, unless StrictMask is set.
+	Mask FieldFilter
+
+	// StrictMask turns a field disallowed by Mask into an error instead of
+	// a silent skip. Has no effect if Mask is nil.
+	StrictMask bool
+
+	// DefaultSliceStrategy is the merge strategy used for slice fields that
+	// carry no `patch:"..."` tag. Defaults to SliceReplace.
+	DefaultSliceStrategy SliceStrategy
+}
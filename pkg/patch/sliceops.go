@@ -0,0 +1,309 @@
+package patch
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fatih/structs"
+)
+
+// SliceStrategy controls how Apply merges an incoming slice value into a
+// destination slice field, chosen via a `patch:"..."` struct tag read
+// alongside `json:"..."`.
+type SliceStrategy string
+
+const (
+	// SliceReplace replaces the destination slice wholesale. This is the
+	// default when a field carries no `patch` tag.
+	SliceReplace SliceStrategy = "replace"
+	// SliceAppend concatenates incoming elements onto the existing slice.
+	SliceAppend SliceStrategy = "append"
+	// SliceMerge treats the slice as a set keyed by a named JSON field of
+	// its element struct: incoming elements whose key matches an existing
+	// one are merged onto it in place, and elements with no match are
+	// appended. Under ApplyMergePatch, an element carrying the key plus
+	// explicit null for every other field removes the matched entry.
+	SliceMerge SliceStrategy = "merge"
+)
+
+// sliceTag is the parsed form of a `patch:"..."` tag, e.g. `patch:"merge,key=id"`.
+type sliceTag struct {
+	strategy SliceStrategy
+	key      string
+}
+
+func parseSliceTag(tag string, fallback SliceStrategy) (sliceTag, error) {
+	if tag == "" {
+		if fallback == "" {
+			fallback = SliceReplace
+		}
+		return sliceTag{strategy: fallback}, nil
+	}
+
+	parts := strings.Split(tag, ",")
+	st := sliceTag{strategy: SliceStrategy(strings.TrimSpace(parts[0]))}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+		if k == "key" {
+			st.key = v
+		}
+	}
+	switch st.strategy {
+	case SliceReplace, SliceAppend:
+		return st, nil
+	case SliceMerge:
+		if st.key == "" {
+			return sliceTag{}, fmt.Errorf(`patch:"merge" requires a key, e.g. patch:"merge,key=id"`)
+		}
+		return st, nil
+	default:
+		return sliceTag{}, fmt.Errorf("unknown patch slice strategy %q", st.strategy)
+	}
+}
+
+// applySliceField updates a slice-typed destination field according to the
+// strategy carried by its `patch` tag (or opts.DefaultSliceStrategy).
+func applySliceField(dstField *structs.Field, name string, value interface{}, opts Options) (changed bool, err error) {
+	dstValue := dstField.Value()
+	dstSliceType := reflect.TypeOf(dstValue)
+	dstElemType := dstSliceType.Elem()
+
+	valueAsArray, ok := value.([]interface{})
+	if !ok {
+		return false, fmt.Errorf("%v is not an array", name)
+	}
+
+	tag, err := parseSliceTag(dstField.Tag("patch"), opts.DefaultSliceStrategy)
+	if err != nil {
+		return false, fmt.Errorf("%v: %w", name, err)
+	}
+
+	switch tag.strategy {
+	case SliceAppend:
+		return applySliceAppend(dstField, dstSliceType, dstElemType, valueAsArray, name, opts)
+	case SliceMerge:
+		return applySliceMerge(dstField, dstSliceType, dstElemType, valueAsArray, tag.key, opts)
+	default:
+		return applySliceReplace(dstField, dstSliceType, dstElemType, valueAsArray, name, opts)
+	}
+}
+
+func applySliceReplace(dstField *structs.Field, dstSliceType, dstElemType reflect.Type, valueAsArray []interface{}, name string, opts Options) (bool, error) {
+	elemType := dstElemType
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+
+	castedArray := reflect.MakeSlice(dstSliceType, len(valueAsArray), len(valueAsArray))
+	for i, srcElemValue := range valueAsArray {
+		converted, err := convertSliceElement(srcElemValue, dstElemType, elemType, name, opts)
+		if err != nil {
+			return false, err
+		}
+		castedArray.Index(i).Set(converted)
+	}
+
+	if err := dstField.Set(castedArray.Interface()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func applySliceAppend(dstField *structs.Field, dstSliceType, dstElemType reflect.Type, valueAsArray []interface{}, name string, opts Options) (bool, error) {
+	elemType := dstElemType
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+
+	result := reflect.ValueOf(dstField.Value())
+	for _, srcElemValue := range valueAsArray {
+		converted, err := convertSliceElement(srcElemValue, dstElemType, elemType, name, opts)
+		if err != nil {
+			return false, err
+		}
+		result = reflect.Append(result, converted)
+	}
+
+	if err := dstField.Set(result.Interface()); err != nil {
+		return false, err
+	}
+	return len(valueAsArray) > 0, nil
+}
+
+func applySliceMerge(dstField *structs.Field, dstSliceType, dstElemType reflect.Type, valueAsArray []interface{}, key string, opts Options) (bool, error) {
+	elemType := dstElemType
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return false, fmt.Errorf(`patch:"merge" requires a slice of structs`)
+	}
+
+	existing := reflect.ValueOf(dstField.Value())
+	result := reflect.MakeSlice(dstSliceType, existing.Len(), existing.Len())
+	reflect.Copy(result, existing)
+
+	var changed bool
+	for _, srcElemValue := range valueAsArray {
+		if srcElemValue == nil {
+			// a bare null carries no key to match a removal against; use
+			// the key-plus-all-other-fields-null form below instead
+			continue
+		}
+
+		valueToApply, isObj := srcElemValue.(map[string]interface{})
+		if !isObj {
+			return false, fmt.Errorf(`patch:"merge" elements must be objects`)
+		}
+		keyValue, hasKey := valueToApply[key]
+		if !hasKey {
+			return false, fmt.Errorf("merge element is missing key field %q", key)
+		}
+
+		idx := findSliceElementByKey(result, elemType, key, keyValue)
+
+		// Under ApplyMergePatch, an element that carries the key plus
+		// explicit nulls for every other field removes the matched entry,
+		// mirroring how a null map entry deletes a key. A no-match is a
+		// no-op: nothing to remove.
+		if opts.MergePatch && isMergeDeleteElement(valueToApply, key, elemType) {
+			if idx >= 0 {
+				reflect.Copy(result.Slice(idx, result.Len()), result.Slice(idx+1, result.Len()))
+				result = result.Slice(0, result.Len()-1)
+				changed = true
+			}
+			continue
+		}
+
+		if idx >= 0 {
+			target := result.Index(idx)
+			if dstElemType.Kind() == reflect.Pointer {
+				target = target.Elem()
+			}
+			iChanged, err := ApplyWithOptions(target.Addr().Interface(), valueToApply, opts)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || iChanged
+			continue
+		}
+
+		newElem := reflect.New(elemType)
+		if _, err := ApplyWithOptions(newElem.Interface(), valueToApply, opts); err != nil {
+			return false, err
+		}
+		elemValue := newElem.Elem()
+		if dstElemType.Kind() == reflect.Pointer {
+			elemValue = newElem
+		}
+		result = reflect.Append(result, elemValue)
+		changed = true
+	}
+
+	if err := dstField.Set(result.Interface()); err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
+// isMergeDeleteElement reports whether valueToApply is a removal directive
+// for the merge strategy: every JSON field of elemType other than key must
+// be present and explicit null. A patch that only nulls some of the
+// struct's fields is a normal partial merge-patch update, not a delete.
+func isMergeDeleteElement(valueToApply map[string]interface{}, key string, elemType reflect.Type) bool {
+	otherFields := structJSONFieldNames(elemType, key)
+	if len(otherFields) == 0 {
+		return false
+	}
+	for _, name := range otherFields {
+		v, present := valueToApply[name]
+		if !present || v != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// structJSONFieldNames returns the json-tag names of t's exported fields,
+// recursing into embedded structs the way fieldByJSONTag does, and skipping
+// the named exclude (typically the merge key) and any field tagged json:"-".
+func structJSONFieldNames(t reflect.Type, exclude string) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		if sf.Anonymous {
+			embedded := sf.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				names = append(names, structJSONFieldNames(embedded, exclude)...)
+			}
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "" {
+			name = sf.Name
+		}
+		if name == "-" || name == exclude {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// findSliceElementByKey returns the index of the element of s whose keyField
+// (matched by json tag) equals keyValue, or -1 if none matches. keyValue ==
+// nil matches nothing (used when removing by key alone isn't meaningful).
+func findSliceElementByKey(s reflect.Value, elemType reflect.Type, keyField string, keyValue interface{}) int {
+	if keyValue == nil {
+		return -1
+	}
+	for i := 0; i < s.Len(); i++ {
+		elem := s.Index(i)
+		if elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				continue
+			}
+			elem = elem.Elem()
+		}
+		fv, ok := fieldByJSONTag(elem, keyField)
+		if !ok {
+			continue
+		}
+		converted, err := convertJSONValue(keyValue, fv.Type())
+		if err != nil {
+			continue
+		}
+		if reflect.DeepEqual(converted.Interface(), fv.Interface()) {
+			return i
+		}
+	}
+	return -1
+}
+
+// convertSliceElement converts a single incoming array element (an object
+// recursing through Apply, or a scalar converted directly) into dstElemType.
+func convertSliceElement(srcElemValue interface{}, dstElemType, structElemType reflect.Type, name string, opts Options) (reflect.Value, error) {
+	if valueToApply, isStruct := srcElemValue.(map[string]interface{}); isStruct {
+		newArrayElem := reflect.New(structElemType)
+		if _, err := ApplyWithOptions(newArrayElem.Interface(), valueToApply, opts); err != nil {
+			return reflect.Value{}, err
+		}
+		if dstElemType.Kind() == reflect.Pointer {
+			return newArrayElem, nil
+		}
+		return newArrayElem.Elem(), nil
+	}
+
+	reflectSrcElemValue := reflect.ValueOf(srcElemValue)
+	if !reflectSrcElemValue.CanConvert(dstElemType) {
+		return reflect.Value{}, fmt.Errorf("can't convert %v to dst type", name)
+	}
+	return reflectSrcElemValue.Convert(dstElemType), nil
+}
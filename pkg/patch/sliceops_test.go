@@ -0,0 +1,225 @@
+package patch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSliceDefaultStrategyReplaces(t *testing.T) {
+	type Target struct {
+		Characters []string `json:"characters"`
+	}
+	a := Target{Characters: []string{"Anakin Skywalker", "Obi-Wan Kenobi"}}
+
+	data := `{"characters":["Darth Vader"]}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, []string{"Darth Vader"}, a.Characters)
+}
+
+func TestSliceAppendTagConcatenates(t *testing.T) {
+	type Target struct {
+		Characters []string `json:"characters" patch:"append"`
+	}
+	a := Target{Characters: []string{"Anakin Skywalker"}}
+
+	data := `{"characters":["Luke Skywalker"]}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, []string{"Anakin Skywalker", "Luke Skywalker"}, a.Characters)
+}
+
+func TestSliceMergeTagUpdatesMatchingElementByKey(t *testing.T) {
+	type Character struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type Target struct {
+		Characters []Character `json:"characters" patch:"merge,key=id"`
+	}
+	a := Target{Characters: []Character{{ID: "luke", Name: "Luke"}, {ID: "leia", Name: "Leia"}}}
+
+	data := `{"characters":[{"id":"luke","name":"Skywalker"}]}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, []Character{{ID: "luke", Name: "Skywalker"}, {ID: "leia", Name: "Leia"}}, a.Characters)
+}
+
+func TestSliceMergeTagAppendsUnmatchedElement(t *testing.T) {
+	type Character struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type Target struct {
+		Characters []Character `json:"characters" patch:"merge,key=id"`
+	}
+	a := Target{Characters: []Character{{ID: "luke", Name: "Luke"}}}
+
+	data := `{"characters":[{"id":"leia","name":"Leia"}]}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, []Character{{ID: "luke", Name: "Luke"}, {ID: "leia", Name: "Leia"}}, a.Characters)
+}
+
+func TestSliceMergeTagRequiresKeyField(t *testing.T) {
+	type Character struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type Target struct {
+		Characters []Character `json:"characters" patch:"merge"`
+	}
+	a := Target{Characters: []Character{{ID: "luke", Name: "Luke"}}}
+
+	data := `{"characters":[{"id":"luke","name":"Skywalker"}]}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	_, err := Apply(&a, p)
+	assert.Error(t, err)
+}
+
+func TestSliceMergePatchNullElementRemovesMatchedEntry(t *testing.T) {
+	type Character struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type Target struct {
+		Characters []Character `json:"characters" patch:"merge,key=id"`
+	}
+	a := Target{Characters: []Character{{ID: "luke", Name: "Luke"}, {ID: "leia", Name: "Leia"}}}
+
+	data := `{"characters":[{"id":"luke","name":null}]}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := ApplyMergePatch(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, []Character{{ID: "leia", Name: "Leia"}}, a.Characters)
+}
+
+func TestSliceMergePatchNullElementWithoutMatchIsNoOp(t *testing.T) {
+	type Character struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type Target struct {
+		Characters []Character `json:"characters" patch:"merge,key=id"`
+	}
+	a := Target{Characters: []Character{{ID: "leia", Name: "Leia"}}}
+
+	data := `{"characters":[{"id":"luke","name":null}]}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := ApplyMergePatch(&a, p)
+	assert.NoError(t, err)
+	assert.False(t, chg)
+	assert.Equal(t, []Character{{ID: "leia", Name: "Leia"}}, a.Characters)
+}
+
+func TestSliceMergeWithoutMergePatchSkipsNullFieldInsteadOfRemoving(t *testing.T) {
+	type Character struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type Target struct {
+		Characters []Character `json:"characters" patch:"merge,key=id"`
+	}
+	a := Target{Characters: []Character{{ID: "luke", Name: "Luke"}}}
+
+	data := `{"characters":[{"id":"luke","name":null}]}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.False(t, chg)
+	assert.Equal(t, []Character{{ID: "luke", Name: "Luke"}}, a.Characters)
+}
+
+func TestSliceMergeBareNullElementIsSkipped(t *testing.T) {
+	type Character struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type Target struct {
+		Characters []Character `json:"characters" patch:"merge,key=id"`
+	}
+	a := Target{Characters: []Character{{ID: "luke", Name: "Luke"}}}
+
+	data := `{"characters":[null]}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := ApplyMergePatch(&a, p)
+	assert.NoError(t, err)
+	assert.False(t, chg)
+	assert.Equal(t, []Character{{ID: "luke", Name: "Luke"}}, a.Characters)
+}
+
+func TestSliceMergePatchPartialNullDoesNotDeleteEntry(t *testing.T) {
+	type Character struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Nickname string `json:"nickname"`
+	}
+	type Target struct {
+		Characters []Character `json:"characters" patch:"merge,key=id"`
+	}
+	a := Target{Characters: []Character{{ID: "luke", Name: "Luke", Nickname: "Red Five"}}}
+
+	data := `{"characters":[{"id":"luke","nickname":null}]}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := ApplyMergePatch(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, []Character{{ID: "luke", Name: "Luke", Nickname: ""}}, a.Characters)
+}
+
+func TestSliceMergePatchNullElementDeletesWithEmbeddedField(t *testing.T) {
+	type Metadata struct {
+		Name string `json:"name"`
+	}
+	type Character struct {
+		Metadata
+		ID string `json:"id"`
+	}
+	type Target struct {
+		Characters []Character `json:"characters" patch:"merge,key=id"`
+	}
+	a := Target{Characters: []Character{
+		{Metadata: Metadata{Name: "Luke"}, ID: "luke"},
+		{Metadata: Metadata{Name: "Leia"}, ID: "leia"},
+	}}
+
+	data := `{"characters":[{"id":"luke","name":null}]}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := ApplyMergePatch(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, []Character{{Metadata: Metadata{Name: "Leia"}, ID: "leia"}}, a.Characters)
+}
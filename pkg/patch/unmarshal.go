@@ -0,0 +1,51 @@
+package patch
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+)
+
+// unmarshalScalar tries to decode value into a new instance of dstType via
+// json.Unmarshaler or encoding.TextUnmarshaler, the same way encoding/json
+// itself would. This lets Apply patch fields whose type owns its own
+// decoding, such as time.Time, net.IP, big.Int or a custom UUID type,
+// without the caller writing a wrapper type.
+//
+// ok is false when dstType implements neither interface, or implements only
+// encoding.TextUnmarshaler and value is not a string, in which case the
+// caller should fall back to the regular reflect-based conversion.
+func unmarshalScalar(dstType reflect.Type, value interface{}) (result reflect.Value, ok bool, err error) {
+	elemType := dstType
+	if dstType.Kind() == reflect.Pointer {
+		elemType = dstType.Elem()
+	}
+
+	instance := reflect.New(elemType) // always *elemType
+
+	switch dst := instance.Interface().(type) {
+	case json.Unmarshaler:
+		data, merr := json.Marshal(value)
+		if merr != nil {
+			return reflect.Value{}, true, merr
+		}
+		if uerr := dst.UnmarshalJSON(data); uerr != nil {
+			return reflect.Value{}, true, uerr
+		}
+	case encoding.TextUnmarshaler:
+		s, isString := value.(string)
+		if !isString {
+			return reflect.Value{}, false, nil
+		}
+		if uerr := dst.UnmarshalText([]byte(s)); uerr != nil {
+			return reflect.Value{}, true, uerr
+		}
+	default:
+		return reflect.Value{}, false, nil
+	}
+
+	if dstType.Kind() == reflect.Pointer {
+		return instance, true, nil
+	}
+	return instance.Elem(), true, nil
+}
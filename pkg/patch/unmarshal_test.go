@@ -0,0 +1,59 @@
+package patch
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyUnmarshalsTimeTime(t *testing.T) {
+	type Target struct {
+		CreatedAt time.Time `json:"created_at"`
+	}
+	a := Target{}
+
+	data := `{"created_at": "2016-01-02T15:04:05Z"}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, "2016-01-02T15:04:05Z", a.CreatedAt.Format(time.RFC3339))
+}
+
+func TestApplyUnmarshalsTextUnmarshaler(t *testing.T) {
+	type Target struct {
+		Address net.IP `json:"address"`
+	}
+	a := Target{}
+
+	data := `{"address": "192.0.2.1"}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.Equal(t, "192.0.2.1", a.Address.String())
+}
+
+func TestApplyUnmarshalsPointerToTime(t *testing.T) {
+	type Target struct {
+		DeletedAt *time.Time `json:"deleted_at"`
+	}
+	a := Target{}
+
+	data := `{"deleted_at": "2016-01-02T15:04:05Z"}`
+	p := make(map[string]interface{})
+	assert.NoError(t, json.Unmarshal([]byte(data), &p))
+
+	chg, err := Apply(&a, p)
+	assert.NoError(t, err)
+	assert.True(t, chg)
+	assert.NotNil(t, a.DeletedAt)
+	assert.Equal(t, "2016-01-02T15:04:05Z", a.DeletedAt.Format(time.RFC3339))
+}